@@ -0,0 +1,254 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Backend receives every Entry the logger dispatches and decides,
+// independently of any other backend, whether and how to persist it. The
+// logger's own level only screens entries for the implicit writer backend;
+// backends set via Backends(...) see every level.
+type Backend interface {
+	Log(Entry) error
+}
+
+func formatterOrDefault(f Formatter) Formatter {
+	if f == nil {
+		return TextFormatter{}
+	}
+	return f
+}
+
+// WriterBackend writes formatted entries to an io.Writer.
+type WriterBackend struct {
+	Writer    io.Writer
+	Formatter Formatter
+	Level     level
+	ColorMode ColorMode
+
+	colorOnce    sync.Once
+	colorEnabled bool
+}
+
+func (b *WriterBackend) Log(e Entry) error {
+	if !levelEnabled(b.Level, e.Lvl) {
+		return nil
+	}
+
+	b.colorOnce.Do(func() {
+		b.colorEnabled = resolveColor(b.ColorMode, b.Writer)
+	})
+	e.ColorEnabled = b.colorEnabled
+
+	data, err := formatterOrDefault(b.Formatter).Format(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.Writer.Write(data)
+	return err
+}
+
+// FileBackend writes formatted entries to a file, rotating it before a
+// write that would take it past MaxSize bytes, or before the first write
+// after it has been open longer than MaxAge. A zero value for either
+// disables that rotation trigger.
+type FileBackend struct {
+	Path      string
+	Formatter Formatter
+	Level     level
+	MaxSize   int64
+	MaxAge    time.Duration
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// NewFileBackend opens (or creates) Path for appending and returns a ready
+// to use FileBackend.
+func NewFileBackend(path string, formatter Formatter, lvl level, maxSize int64, maxAge time.Duration) (*FileBackend, error) {
+	b := &FileBackend{
+		Path:      path,
+		Formatter: formatter,
+		Level:     lvl,
+		MaxSize:   maxSize,
+		MaxAge:    maxAge,
+	}
+
+	if err := b.open(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (b *FileBackend) open() error {
+	info, err := os.Stat(b.Path)
+
+	f, err2 := os.OpenFile(b.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err2 != nil {
+		return err2
+	}
+
+	b.file = f
+	b.opened = time.Now()
+	if err == nil {
+		b.size = info.Size()
+	} else {
+		b.size = 0
+	}
+
+	return nil
+}
+
+func (b *FileBackend) Log(e Entry) error {
+	if !levelEnabled(b.Level, e.Lvl) {
+		return nil
+	}
+
+	data, err := formatterOrDefault(b.Formatter).Format(e)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.rotateIfNeeded(int64(len(data))); err != nil {
+		return err
+	}
+
+	n, err := b.file.Write(data)
+	b.size += int64(n)
+
+	return err
+}
+
+func (b *FileBackend) rotateIfNeeded(next int64) error {
+	sizeExceeded := b.MaxSize > 0 && b.size+next > b.MaxSize
+	ageExceeded := b.MaxAge > 0 && time.Since(b.opened) > b.MaxAge
+
+	if !sizeExceeded && !ageExceeded {
+		return nil
+	}
+
+	return b.rotate()
+}
+
+func (b *FileBackend) rotate() error {
+	if b.file != nil {
+		b.file.Close()
+	}
+
+	rotated := fmt.Sprintf("%s.%s", b.Path, time.Now().UTC().Format("20060102T150405"))
+	if _, err := os.Stat(b.Path); err == nil {
+		if err := os.Rename(b.Path, rotated); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(b.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	b.file = f
+	b.size = 0
+	b.opened = time.Now()
+
+	return nil
+}
+
+// Close flushes and closes the underlying file.
+func (b *FileBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.file.Close()
+}
+
+// DropPolicy controls what AsyncBackend does when its buffer is full.
+type DropPolicy int
+
+const (
+	// DropNewest discards the entry that just arrived.
+	DropNewest = DropPolicy(iota)
+	// DropOldest discards the oldest buffered entry to make room.
+	DropOldest
+	// Block waits for room in the buffer, applying backpressure on callers.
+	Block
+)
+
+// AsyncBackend wraps another Backend with a buffered channel so that Log
+// calls don't wait on slow I/O while the buffer has room. Once the buffer
+// fills up, the configured DropPolicy applies; under Block, Log waits.
+type AsyncBackend struct {
+	backend Backend
+	entries chan Entry
+	policy  DropPolicy
+	done    chan struct{}
+}
+
+// NewAsyncBackend starts a goroutine draining entries into backend through
+// a channel of the given buffer size.
+func NewAsyncBackend(backend Backend, bufferSize int, policy DropPolicy) *AsyncBackend {
+	b := &AsyncBackend{
+		backend: backend,
+		entries: make(chan Entry, bufferSize),
+		policy:  policy,
+		done:    make(chan struct{}),
+	}
+
+	go b.run()
+
+	return b
+}
+
+func (b *AsyncBackend) run() {
+	for e := range b.entries {
+		// Best-effort: there is no caller left to hand the error back to.
+		b.backend.Log(e)
+	}
+	close(b.done)
+}
+
+func (b *AsyncBackend) Log(e Entry) error {
+	switch b.policy {
+	case Block:
+		b.entries <- e
+	case DropOldest:
+		select {
+		case b.entries <- e:
+		default:
+			select {
+			case <-b.entries:
+			default:
+			}
+			select {
+			case b.entries <- e:
+			default:
+			}
+		}
+	default: // DropNewest
+		select {
+		case b.entries <- e:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Close stops accepting new entries and waits for the buffered ones to
+// drain into the wrapped backend.
+func (b *AsyncBackend) Close() error {
+	close(b.entries)
+	<-b.done
+	return nil
+}
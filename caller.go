@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Flag bits, mirroring the stdlib "log" package plus Lmodule for the
+// pkg.(*Type).Method caller style this package originally always used.
+const (
+	Ldate = 1 << iota
+	Ltime
+	Lmicroseconds
+	Llongfile
+	Lshortfile
+	Lmodule
+)
+
+var modulePattern = regexp.MustCompile(`\(.*\)\.\w*.*?`)
+
+var funcNames sync.Map // map[uintptr]string
+
+// callerBaseSkip is the number of stack frames between runtime.Caller and
+// the user code that invoked a level method such as Error, before any
+// WithCallerSkip/CallerSkip adjustment: callerInfo -> log -> Error -> user.
+const callerBaseSkip = 3
+
+// WithCallerSkip returns a child logger that skips n extra frames when
+// locating the call site, for use by adapters and middleware that wrap
+// Logger behind their own logging methods.
+func (self *logger) WithCallerSkip(n int) Logger {
+	child := *self
+	child.callerSkip = self.callerSkip + n
+	return &child
+}
+
+// callerInfo renders the call site of the in-flight log call, as either
+// file:line (Lshortfile/Llongfile) or the pkg.(*Type).Method form used by
+// default.
+func (self *logger) callerInfo() string {
+	pc, file, line, ok := runtime.Caller(callerBaseSkip + self.callerSkip)
+	if !ok {
+		return "unknown caller"
+	}
+
+	if self.flags&(Lshortfile|Llongfile) != 0 {
+		if self.flags&Lshortfile != 0 {
+			file = filepath.Base(file)
+		}
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+
+	return moduleName(pc)
+}
+
+// moduleName extracts the pkg.(*Type).Method portion of the function
+// running at pc, caching the result so repeated calls from the same call
+// site skip runtime.FuncForPC and the regexp match.
+func moduleName(pc uintptr) string {
+	if cached, ok := funcNames.Load(pc); ok {
+		return cached.(string)
+	}
+
+	details := runtime.FuncForPC(pc)
+	if details == nil {
+		return "unknown caller"
+	}
+
+	name := details.Name()
+	if match := modulePattern.FindString(name); match != "" {
+		name = match
+	}
+
+	funcNames.Store(pc, name)
+	return name
+}
+
+// formatFlagsTime renders t per the Ldate/Ltime/Lmicroseconds bits. With
+// none of those set, it falls back to the original RFC3339 timestamp.
+func formatFlagsTime(t time.Time, flags int) string {
+	if flags&(Ldate|Ltime|Lmicroseconds) == 0 {
+		return t.UTC().Format(timeFormat)
+	}
+
+	s := ""
+	if flags&Ldate != 0 {
+		s += t.UTC().Format("2006-01-02")
+	}
+
+	if flags&(Ltime|Lmicroseconds) != 0 {
+		if s != "" {
+			s += " "
+		}
+		layout := "15:04:05"
+		if flags&Lmicroseconds != 0 {
+			layout = "15:04:05.000000"
+		}
+		s += t.UTC().Format(layout)
+	}
+
+	return s
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// ColorMode controls whether a WriterBackend emits ANSI color escapes.
+type ColorMode int
+
+const (
+	// Auto emits color only when the backend's writer looks like a
+	// terminal, avoiding corrupting file output or piped logs.
+	Auto = ColorMode(iota)
+	// Always emits color unconditionally.
+	Always
+	// Never strips all color escapes.
+	Never
+)
+
+// Colorize sets the ColorMode used by the logger's implicit WriterBackend.
+// A WriterBackend built via Backends(...) instead sets its own ColorMode
+// field.
+func Colorize(mode ColorMode) option {
+	return func(log *logger) {
+		log.colorMode = mode
+	}
+}
+
+// resolveColor decides whether w should receive ANSI escapes under mode,
+// enabling VT processing on Windows consoles where necessary.
+func resolveColor(mode ColorMode, w io.Writer) bool {
+	f, isFile := w.(*os.File)
+
+	switch mode {
+	case Never:
+		return false
+	case Always:
+		if isFile {
+			enableANSI(f)
+		}
+		return true
+	default: // Auto
+		if !isFile || !isTerminal(f) {
+			return false
+		}
+		enableANSI(f)
+		return true
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
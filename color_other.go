@@ -0,0 +1,10 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import "os"
+
+// enableANSI is a no-op outside Windows: every other supported terminal
+// already understands ANSI escapes natively.
+func enableANSI(f *os.File) {}
@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// enableANSI turns on ENABLE_VIRTUAL_TERMINAL_PROCESSING for f so that
+// ANSI escapes render instead of printing as garbage on older Windows
+// consoles. Best-effort: failures are ignored, and resolveColor still
+// reports color as enabled for f.
+func enableANSI(f *os.File) {
+	handle := syscall.Handle(f.Fd())
+
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return
+	}
+
+	setConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+}
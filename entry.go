@@ -0,0 +1,18 @@
+package logger
+
+import "time"
+
+// Entry is the structured record produced by each log call before it is
+// handed to a Formatter.
+type Entry struct {
+	Time         time.Time
+	TimeText     string
+	Level        string
+	Lvl          level
+	Tag          string
+	Color        color
+	Caller       string
+	Message      string
+	Fields       map[string]interface{}
+	ColorEnabled bool
+}
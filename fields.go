@@ -0,0 +1,47 @@
+package logger
+
+import "context"
+
+// ContextExtractorFunc pulls structured fields (e.g. a request or trace ID)
+// out of a context.Context for attachment via WithContext.
+type ContextExtractorFunc func(ctx context.Context) map[string]interface{}
+
+// ContextExtractor configures the function used by Logger.WithContext to
+// derive fields from a context.Context.
+func ContextExtractor(f ContextExtractorFunc) option {
+	return func(log *logger) {
+		log.extractor = f
+	}
+}
+
+func (self *logger) With(key string, value interface{}) Logger {
+	return self.withFields(map[string]interface{}{key: value})
+}
+
+func (self *logger) WithFields(fields map[string]interface{}) Logger {
+	return self.withFields(fields)
+}
+
+func (self *logger) WithContext(ctx context.Context) Logger {
+	if self.extractor == nil {
+		return self
+	}
+	return self.withFields(self.extractor(ctx))
+}
+
+// withFields returns a child logger whose fields are the receiver's fields
+// merged with the given ones, leaving the receiver untouched.
+func (self *logger) withFields(fields map[string]interface{}) Logger {
+	merged := make(map[string]interface{}, len(self.fields)+len(fields))
+	for k, v := range self.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	child := *self
+	child.fields = merged
+
+	return &child
+}
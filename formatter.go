@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Formatter renders an Entry into the bytes written to a backend.
+type Formatter interface {
+	Format(Entry) ([]byte, error)
+}
+
+// TextFormatter reproduces the original human-readable output.
+type TextFormatter struct{}
+
+func (TextFormatter) Format(e Entry) ([]byte, error) {
+	var b strings.Builder
+
+	ts := e.TimeText
+
+	level := fmt.Sprintf("%5s", e.Level)
+	if e.ColorEnabled {
+		level = fmt.Sprintf("%s%s%s", levelColor(e.Level), level, NoColor)
+	}
+
+	if e.Tag != "" {
+		tag := e.Tag
+		if e.ColorEnabled {
+			tag = fmt.Sprintf("%s%s%s", e.Color, e.Tag, NoColor)
+		}
+		fmt.Fprintf(&b, "[%s] [%s] [%s] [%s] %s", ts, level, tag, e.Caller, e.Message)
+	} else {
+		fmt.Fprintf(&b, "[%s] [%s] [%s] %s", ts, level, e.Caller, e.Message)
+	}
+
+	for _, k := range sortedFieldKeys(e.Fields) {
+		fmt.Fprintf(&b, " %s=%v", k, e.Fields[k])
+	}
+
+	b.WriteByte('\n')
+	return []byte(b.String()), nil
+}
+
+// levelColor maps a level label onto the color it's highlighted with in
+// text output; levels with no entry here (e.g. INFO) are left uncolored.
+func levelColor(label string) color {
+	switch label {
+	case "ERROR", "FATAL":
+		return Red
+	case "WARN":
+		return Yellow
+	case "DEBUG", "TRACE":
+		return Gray
+	default:
+		return NoColor
+	}
+}
+
+// JSONFormatter emits one JSON object per line, suitable for ingestion by
+// log aggregators such as ELK or Loki.
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(e Entry) ([]byte, error) {
+	m := make(map[string]interface{}, len(e.Fields)+5)
+	for k, v := range e.Fields {
+		m[k] = v
+	}
+	m["ts"] = e.Time.UTC().Format(timeFormat)
+	m["level"] = e.Level
+	m["tag"] = e.Tag
+	m["caller"] = e.Caller
+	m["msg"] = e.Message
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// LogfmtFormatter emits key=value pairs, one record per line.
+type LogfmtFormatter struct{}
+
+func (LogfmtFormatter) Format(e Entry) ([]byte, error) {
+	var b strings.Builder
+
+	writeLogfmtPair(&b, "ts", e.Time.UTC().Format(timeFormat))
+	b.WriteByte(' ')
+	writeLogfmtPair(&b, "level", e.Level)
+	if e.Tag != "" {
+		b.WriteByte(' ')
+		writeLogfmtPair(&b, "tag", e.Tag)
+	}
+	b.WriteByte(' ')
+	writeLogfmtPair(&b, "caller", e.Caller)
+	b.WriteByte(' ')
+	writeLogfmtPair(&b, "msg", e.Message)
+
+	for _, k := range sortedFieldKeys(e.Fields) {
+		b.WriteByte(' ')
+		writeLogfmtPair(&b, k, fmt.Sprint(e.Fields[k]))
+	}
+
+	b.WriteByte('\n')
+	return []byte(b.String()), nil
+}
+
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	b.WriteString(key)
+	b.WriteByte('=')
+	if strings.ContainsAny(value, " \t\r\n\"=") {
+		b.WriteString(fmt.Sprintf("%q", value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+func sortedFieldKeys(fields map[string]interface{}) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,46 @@
+package logger
+
+// level is a bitmask, not an ordinal, so callers can enable arbitrary
+// subsets such as Debug|Error without also enabling Warn|Info.
+type level uint
+
+const (
+	Trace = level(1 << iota)
+	Debug
+	Info
+	Warn
+	Error
+	Highlight
+	// Fatal always clears every level filter: a Fatal record is logged
+	// regardless of the logger's or a backend's configured mask.
+	Fatal
+)
+
+// AllLevels enables every level, including Trace and Highlight.
+const AllLevels = Trace | Debug | Info | Warn | Error | Highlight | Fatal
+
+// defaultLevel matches the pre-bitmask default of Info and above.
+const defaultLevel = Info | Warn | Error | Fatal
+
+// levelEnabled reports whether lvl clears the given mask.
+func levelEnabled(mask, lvl level) bool {
+	return lvl == Fatal || mask&lvl != 0
+}
+
+// Enabled reports whether lvl is enabled for this logger, so callers can
+// guard expensive argument construction:
+//
+//	if log.Enabled(logger.Debug) {
+//		log.Debug(expensive())
+//	}
+//
+// Once explicit Backends(...) are configured, the logger's own Level no
+// longer gates records — each backend's Level filters independently — so
+// Enabled reports true for any level and expensive-argument guards should
+// rely on the backends' own thresholds instead.
+func (self *logger) Enabled(lvl level) bool {
+	if self.explicitBackends {
+		return true
+	}
+	return levelEnabled(self.level, lvl)
+}
@@ -1,24 +1,14 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"hash/fnv"
 	"io"
 	"os"
-	"regexp"
-	"runtime"
 	"time"
 )
 
-const (
-	Debug = level(iota)
-	Info  = level(iota)
-	Warn  = level(iota)
-	Error = level(iota)
-)
-
-type level int
-
 const (
 	NoColor = color("\x1b[0m")
 	Red     = color("\x1b[91m")
@@ -28,6 +18,7 @@ const (
 	Magenta = color("\x1b[35m")
 	Cyan    = color("\x1b[36m")
 	White   = color("\x1b[37m")
+	Gray    = color("\x1b[90m")
 )
 
 type color string
@@ -42,11 +33,7 @@ var colors = []color{
 	White,
 }
 
-var pattern *regexp.Regexp
-
-func init() {
-	pattern = regexp.MustCompile(`\(.*\)\.\w*.*?`)
-}
+const timeFormat = time.RFC3339
 
 type option func(*logger)
 
@@ -54,16 +41,23 @@ func New(tag string, opts ...option) Logger {
 	color := colors[int(hash(tag))%len(colors)]
 
 	logger := &logger{
-		tag,
-		color,
-		Info,
-		os.Stdout,
+		tag:       tag,
+		color:     color,
+		level:     defaultLevel,
+		writer:    os.Stdout,
+		formatter: TextFormatter{},
 	}
 
 	for _, opt := range opts {
 		opt(logger)
 	}
 
+	if logger.backends == nil {
+		logger.backends = []Backend{
+			&WriterBackend{Writer: logger.writer, Formatter: logger.formatter, Level: AllLevels, ColorMode: logger.colorMode},
+		}
+	}
+
 	return logger
 }
 
@@ -85,6 +79,76 @@ func Level(l level) option {
 	}
 }
 
+// severityOrder lists the levels with an ordinal severity, least to most
+// severe. Highlight has no place in this ordering: it marks records for
+// visibility rather than denoting a severity, so LevelAndAbove never
+// includes it implicitly.
+var severityOrder = []level{Trace, Debug, Info, Warn, Error, Fatal}
+
+// LevelAndAbove sets the mask to l and every level at least as severe,
+// matching the pre-bitmask behavior of Level (where e.g. Level(Debug) meant
+// "Debug, Info, Warn, Error and Fatal"). Prefer this over Level when porting
+// callers that relied on that ordinal semantics; Level(Debug) under the
+// bitmask scheme enables Debug alone.
+func LevelAndAbove(l level) option {
+	return func(log *logger) {
+		var mask level
+		include := false
+		for _, lvl := range severityOrder {
+			if lvl == l {
+				include = true
+			}
+			if include {
+				mask |= lvl
+			}
+		}
+		log.level = mask
+	}
+}
+
+// Format selects the Formatter used to render entries before they are
+// written, e.g. Format(JSONFormatter{}) for log-aggregator-friendly output.
+// It applies to the logger's implicit writer backend only; a backend passed
+// via Backends(...) uses its own Formatter field.
+func Format(f Formatter) option {
+	return func(log *logger) {
+		log.formatter = f
+	}
+}
+
+// Backends replaces the logger's single implicit writer backend with the
+// given set, e.g. a rotating file backend at Debug alongside a syslog
+// backend at Error. Once set, the logger's own Level stops gating records
+// before dispatch: each backend's Level is solely responsible for
+// filtering, so e.g. DEBUG can still reach a file backend while only
+// ERROR also reaches a syslog backend.
+func Backends(backends ...Backend) option {
+	return func(log *logger) {
+		log.backends = backends
+		log.explicitBackends = true
+	}
+}
+
+// CallerSkip adds n extra frames to skip when locating the call site,
+// needed when Logger is wrapped by an adapter or middleware function.
+func CallerSkip(n int) option {
+	return func(log *logger) {
+		log.callerSkip = n
+	}
+}
+
+// Flags selects which parts of the timestamp and which caller format are
+// rendered, mirroring the bits from the standard library "log" package
+// plus Lmodule for the pkg.(*Type).Method style caller. The zero value
+// keeps the original RFC3339 timestamp and pkg.(*Type).Method caller.
+// The timestamp bits affect TextFormatter output only: JSONFormatter and
+// LogfmtFormatter always write an RFC3339 ts.
+func Flags(flags int) option {
+	return func(log *logger) {
+		log.flags = flags
+	}
+}
+
 type Logger interface {
 	Fatal(a ...interface{})
 	Fatalf(format string, a ...interface{})
@@ -100,111 +164,195 @@ type Logger interface {
 
 	Debug(a ...interface{})
 	Debugf(format string, a ...interface{})
+
+	Trace(a ...interface{})
+	Tracef(format string, a ...interface{})
+
+	Highlight(a ...interface{})
+	Highlightf(format string, a ...interface{})
+
+	Enabled(lvl level) bool
+
+	With(key string, value interface{}) Logger
+	WithFields(fields map[string]interface{}) Logger
+	WithContext(ctx context.Context) Logger
+	WithCallerSkip(n int) Logger
 }
 
 type logger struct {
-	tag    string
-	color  color
-	level  level
-	writer io.Writer
+	tag              string
+	color            color
+	level            level
+	writer           io.Writer
+	formatter        Formatter
+	backends         []Backend
+	fields           map[string]interface{}
+	extractor        ContextExtractorFunc
+	sampler          *sampler
+	colorMode        ColorMode
+	callerSkip       int
+	flags            int
+	explicitBackends bool
 }
 
 func (self *logger) Fatal(a ...interface{}) {
-	self.log("FATAL", self.format(a))
+	self.log(Fatal, "FATAL", self.format(a))
 	os.Exit(1)
 }
 
 func (self *logger) Fatalf(f string, a ...interface{}) {
-	self.log("FATAL", self.formatf(f, a))
+	self.log(Fatal, "FATAL", self.formatf(f, a))
 	os.Exit(1)
 }
 
 func (self *logger) Error(a ...interface{}) {
-	if self.level <= Error {
-		self.log("ERROR", self.format(a))
+	if !self.Enabled(Error) {
+		return
 	}
+	if self.sampler != nil && !self.sampler.allow(Error, callerPC(), 0) {
+		return
+	}
+	self.log(Error, "ERROR", self.format(a))
 }
 
 func (self *logger) Errorf(f string, a ...interface{}) {
-	if self.level <= Error {
-		self.log("ERROR", self.formatf(f, a))
+	if !self.Enabled(Error) {
+		return
+	}
+	if self.sampler != nil && !self.sampler.allow(Error, callerPC(), formatPtr(f)) {
+		return
 	}
+	self.log(Error, "ERROR", self.formatf(f, a))
 }
 
 func (self *logger) Warn(a ...interface{}) {
-	if self.level <= Warn {
-		self.log("WARN", self.format(a))
+	if !self.Enabled(Warn) {
+		return
 	}
+	if self.sampler != nil && !self.sampler.allow(Warn, callerPC(), 0) {
+		return
+	}
+	self.log(Warn, "WARN", self.format(a))
 }
 
 func (self *logger) Warnf(f string, a ...interface{}) {
-	if self.level <= Warn {
-		self.log("WARN", self.formatf(f, a))
+	if !self.Enabled(Warn) {
+		return
+	}
+	if self.sampler != nil && !self.sampler.allow(Warn, callerPC(), formatPtr(f)) {
+		return
 	}
+	self.log(Warn, "WARN", self.formatf(f, a))
 }
 
 func (self *logger) Info(a ...interface{}) {
-	if self.level <= Info {
-		self.log("INFO", self.format(a))
+	if !self.Enabled(Info) {
+		return
 	}
+	if self.sampler != nil && !self.sampler.allow(Info, callerPC(), 0) {
+		return
+	}
+	self.log(Info, "INFO", self.format(a))
 }
 
 func (self *logger) Infof(f string, a ...interface{}) {
-	if self.level <= Info {
-		self.log("INFO", self.formatf(f, a))
+	if !self.Enabled(Info) {
+		return
+	}
+	if self.sampler != nil && !self.sampler.allow(Info, callerPC(), formatPtr(f)) {
+		return
 	}
+	self.log(Info, "INFO", self.formatf(f, a))
 }
 
 func (self *logger) Debug(a ...interface{}) {
-	if self.level <= Debug {
-		self.log("DEBUG", self.format(a))
+	if !self.Enabled(Debug) {
+		return
 	}
+	if self.sampler != nil && !self.sampler.allow(Debug, callerPC(), 0) {
+		return
+	}
+	self.log(Debug, "DEBUG", self.format(a))
 }
 
 func (self *logger) Debugf(f string, a ...interface{}) {
-	if self.level <= Debug {
-		self.log("DEBUG", self.formatf(f, a))
+	if !self.Enabled(Debug) {
+		return
+	}
+	if self.sampler != nil && !self.sampler.allow(Debug, callerPC(), formatPtr(f)) {
+		return
 	}
+	self.log(Debug, "DEBUG", self.formatf(f, a))
 }
 
-func (self *logger) format(a []interface{}) string {
-	return fmt.Sprint(a...)
+func (self *logger) Trace(a ...interface{}) {
+	if !self.Enabled(Trace) {
+		return
+	}
+	if self.sampler != nil && !self.sampler.allow(Trace, callerPC(), 0) {
+		return
+	}
+	self.log(Trace, "TRACE", self.format(a))
 }
 
-func (self *logger) formatf(f string, a []interface{}) string {
-	return fmt.Sprintf(f, a...)
+func (self *logger) Tracef(f string, a ...interface{}) {
+	if !self.Enabled(Trace) {
+		return
+	}
+	if self.sampler != nil && !self.sampler.allow(Trace, callerPC(), formatPtr(f)) {
+		return
+	}
+	self.log(Trace, "TRACE", self.formatf(f, a))
 }
 
-func (self *logger) log(level string, s string) {
-	if self.tag != "" {
-		tag := fmt.Sprintf("%s%s%s", self.color, self.tag, NoColor)
-		fmt.Fprintf(self.writer, "[%s] [%5s] [%s] [%s] %s\n", timestamp(), level, tag, caller(), s)
-	} else {
-		fmt.Fprintf(self.writer, "[%s] [%5s] [%s] %s\n", timestamp(), level, caller(), s)
+func (self *logger) Highlight(a ...interface{}) {
+	if !self.Enabled(Highlight) {
+		return
+	}
+	if self.sampler != nil && !self.sampler.allow(Highlight, callerPC(), 0) {
+		return
 	}
+	self.log(Highlight, "HIGHLIGHT", self.format(a))
 }
 
-func timestamp() string {
-	return time.Now().UTC().Format(time.RFC3339)
+func (self *logger) Highlightf(f string, a ...interface{}) {
+	if !self.Enabled(Highlight) {
+		return
+	}
+	if self.sampler != nil && !self.sampler.allow(Highlight, callerPC(), formatPtr(f)) {
+		return
+	}
+	self.log(Highlight, "HIGHLIGHT", self.formatf(f, a))
 }
 
-func caller() string {
-	pc, _, _, ok := runtime.Caller(3)
-	if !ok {
-		return "unknown caller"
-	}
+func (self *logger) format(a []interface{}) string {
+	return fmt.Sprint(a...)
+}
 
-	details := runtime.FuncForPC(pc)
-	if details == nil {
-		return "unknown caller"
-	}
+func (self *logger) formatf(f string, a []interface{}) string {
+	return fmt.Sprintf(f, a...)
+}
 
-	match := pattern.FindString(details.Name())
-	if match != "" {
-		return match
+func (self *logger) log(lvl level, label string, s string) {
+	now := time.Now()
+
+	entry := Entry{
+		Time:     now,
+		TimeText: formatFlagsTime(now, self.flags),
+		Level:    label,
+		Lvl:      lvl,
+		Tag:      self.tag,
+		Color:    self.color,
+		Caller:   self.callerInfo(),
+		Message:  s,
+		Fields:   self.fields,
 	}
 
-	return details.Name()
+	for _, backend := range self.backends {
+		if err := backend.Log(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: backend error: %v\n", err)
+		}
+	}
 }
 
 func hash(s string) uint32 {
@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"container/list"
+	"encoding/binary"
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// SamplerOption configures a sampler installed via the Sampler option.
+type SamplerOption func(*sampler)
+
+// RateLimit caps lvl to perSecond tokens, replenished continuously, with up
+// to burst tokens banked to absorb spikes.
+func RateLimit(lvl level, perSecond float64, burst int) SamplerOption {
+	return func(s *sampler) {
+		s.buckets[lvl] = newTokenBucket(perSecond, burst)
+	}
+}
+
+// Dedup logs the first firstN records seen at a given call site within a
+// one second window, then only every everyM-th one after that.
+func Dedup(firstN, everyM int) SamplerOption {
+	return func(s *sampler) {
+		s.dedupFirstN = firstN
+		s.dedupEveryM = everyM
+	}
+}
+
+// SampleEvery logs only every n-th record at each call site.
+func SampleEvery(n int) SamplerOption {
+	return func(s *sampler) {
+		s.sampleEvery = n
+	}
+}
+
+// Sampler installs rate-limiting and/or sampling on the logger, composed
+// from RateLimit, Dedup and SampleEvery. It is checked before a record's
+// arguments are formatted, so disabled records cost little more than the
+// lookup itself.
+func Sampler(opts ...SamplerOption) option {
+	return func(log *logger) {
+		s := newSampler()
+		for _, opt := range opts {
+			opt(s)
+		}
+		log.sampler = s
+	}
+}
+
+type sampler struct {
+	mu          sync.Mutex
+	buckets     map[level]*tokenBucket
+	dedupFirstN int
+	dedupEveryM int
+	sampleEvery int
+	sites       *siteLRU
+}
+
+func newSampler() *sampler {
+	return &sampler{
+		buckets: make(map[level]*tokenBucket),
+		sites:   newSiteLRU(1024),
+	}
+}
+
+// allow reports whether a record at the given level and call site should
+// be logged. It is safe to call on a nil *sampler (the default: no
+// sampling configured).
+func (s *sampler) allow(lvl level, pc, fmtPtr uintptr) bool {
+	if s == nil {
+		return true
+	}
+
+	if bucket, ok := s.buckets[lvl]; ok && !bucket.allow() {
+		return false
+	}
+
+	if s.dedupEveryM == 0 && s.sampleEvery == 0 {
+		return true
+	}
+
+	key := siteKey(pc, fmtPtr)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	site := s.sites.get(key)
+	site.total++
+
+	allow := true
+
+	if s.dedupEveryM > 0 {
+		now := time.Now()
+		if now.Sub(site.windowStart) > time.Second {
+			site.windowStart = now
+			site.windowCount = 0
+		}
+		site.windowCount++
+
+		if site.windowCount > uint64(s.dedupFirstN) {
+			allow = (site.windowCount-uint64(s.dedupFirstN))%uint64(s.dedupEveryM) == 0
+		}
+	}
+
+	if allow && s.sampleEvery > 1 {
+		allow = site.total%uint64(s.sampleEvery) == 0
+	}
+
+	return allow
+}
+
+func siteKey(pc, fmtPtr uintptr) uint64 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(pc)^uint64(fmtPtr))
+
+	h := fnv.New64a()
+	h.Write(buf[:])
+
+	return h.Sum64()
+}
+
+// callerPC returns the program counter of the function calling the level
+// method (Error, Warnf, ...) that in turn called callerPC.
+func callerPC() uintptr {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return 0
+	}
+	return pc
+}
+
+// formatPtr returns the address of f's backing array, used as a cheap
+// call-site discriminator for format strings (which, being literals, share
+// a backing array across calls at the same call site).
+func formatPtr(f string) uintptr {
+	type stringHeader struct {
+		Data uintptr
+		Len  int
+	}
+	return (*stringHeader)(unsafe.Pointer(&f)).Data
+}
+
+type siteState struct {
+	windowStart time.Time
+	windowCount uint64
+	total       uint64
+}
+
+// siteLRU is a fixed-capacity cache of per-call-site sampling state, so
+// that tracking sampling decisions can't grow unbounded in a long-running
+// process with many call sites.
+type siteLRU struct {
+	capacity int
+	order    *list.List
+	items    map[uint64]*list.Element
+}
+
+type lruEntry struct {
+	key   uint64
+	state *siteState
+}
+
+func newSiteLRU(capacity int) *siteLRU {
+	return &siteLRU{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[uint64]*list.Element),
+	}
+}
+
+func (c *siteLRU) get(key uint64) *siteState {
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*lruEntry).state
+	}
+
+	state := &siteState{windowStart: time.Now()}
+	el := c.order.PushFront(&lruEntry{key: key, state: state})
+	c.items[key] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+
+	return state
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perSecond float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
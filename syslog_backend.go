@@ -0,0 +1,54 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import (
+	"log/syslog"
+	"strings"
+)
+
+// SyslogBackend writes formatted entries to the local syslog daemon,
+// mapping the entry level onto the nearest syslog severity.
+type SyslogBackend struct {
+	writer    *syslog.Writer
+	Formatter Formatter
+	Level     level
+}
+
+// NewSyslogBackend dials the syslog daemon with the given priority and tag.
+func NewSyslogBackend(priority syslog.Priority, tag string, formatter Formatter, lvl level) (*SyslogBackend, error) {
+	w, err := syslog.New(priority, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyslogBackend{writer: w, Formatter: formatter, Level: lvl}, nil
+}
+
+func (b *SyslogBackend) Log(e Entry) error {
+	if !levelEnabled(b.Level, e.Lvl) {
+		return nil
+	}
+
+	data, err := formatterOrDefault(b.Formatter).Format(e)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(string(data), "\n")
+
+	switch {
+	case e.Lvl == Fatal:
+		return b.writer.Crit(msg)
+	case e.Lvl&Error != 0:
+		return b.writer.Err(msg)
+	case e.Lvl&Warn != 0:
+		return b.writer.Warning(msg)
+	case e.Lvl&Highlight != 0:
+		return b.writer.Notice(msg)
+	case e.Lvl&Info != 0:
+		return b.writer.Info(msg)
+	default:
+		return b.writer.Debug(msg)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package logger
+
+import "errors"
+
+// SyslogBackend is unavailable on Windows: log/syslog only supports
+// Unix-like systems.
+type SyslogBackend struct {
+	Formatter Formatter
+	Level     level
+}
+
+// NewSyslogBackend always fails on Windows.
+func NewSyslogBackend(priority int, tag string, formatter Formatter, lvl level) (*SyslogBackend, error) {
+	return nil, errors.New("logger: SyslogBackend is not supported on windows")
+}
+
+func (b *SyslogBackend) Log(e Entry) error {
+	return errors.New("logger: SyslogBackend is not supported on windows")
+}